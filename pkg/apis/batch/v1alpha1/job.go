@@ -0,0 +1,380 @@
+/*
+Copyright 2018 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Job defines the volcano Job.
+type Job struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Spec defines the desired state of the Job.
+	// +optional
+	Spec JobSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+
+	// Status is the current state of the Job.
+	// +optional
+	Status JobStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// JobSpec describes how a Job should be run.
+type JobSpec struct {
+	// MinAvailable is the minimum number of member Pods that must be
+	// scheduled together for the Job to be considered runnable.
+	// +optional
+	MinAvailable int32 `json:"minAvailable,omitempty" protobuf:"varint,1,opt,name=minAvailable"`
+
+	// ActiveDeadlineSeconds bounds how long the Job is allowed to run.
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty" protobuf:"varint,2,opt,name=activeDeadlineSeconds"`
+
+	// Volumes is the list of Job volumes.
+	// +optional
+	Volumes []VolumeSpec `json:"volumes,omitempty" protobuf:"bytes,3,rep,name=volumes"`
+
+	// Tasks specifies the task specification of the Job.
+	// +optional
+	Tasks []TaskSpec `json:"tasks,omitempty" protobuf:"bytes,4,rep,name=tasks"`
+
+	// Policies specifies the Job-level lifecycle policies.
+	// +optional
+	Policies []LifecyclePolicy `json:"policies,omitempty" protobuf:"bytes,5,rep,name=policies"`
+
+	// SchedulerName is the name of the scheduler the Job is submitted to.
+	// +optional
+	SchedulerName string `json:"schedulerName,omitempty" protobuf:"bytes,6,opt,name=schedulerName"`
+
+	// Queue is the name of the queue the Job is submitted to.
+	// +optional
+	Queue string `json:"queue,omitempty" protobuf:"bytes,7,opt,name=queue"`
+}
+
+// TaskSpec specifies the task specification of a Job.
+type TaskSpec struct {
+	// Name specifies the name of tasks.
+	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+
+	// Replicas specifies the replicas of this TaskSpec in the Job.
+	// +optional
+	Replicas int32 `json:"replicas,omitempty" protobuf:"varint,2,opt,name=replicas"`
+
+	// Template is the object that describes the Pod that will be created for
+	// this TaskSpec when executing a Job.
+	Template v1.PodTemplateSpec `json:"template,omitempty" protobuf:"bytes,3,opt,name=template"`
+
+	// Policies specifies the task-level lifecycle policies, which override
+	// the Job-level ones for this TaskSpec.
+	// +optional
+	Policies []LifecyclePolicy `json:"policies,omitempty" protobuf:"bytes,4,rep,name=policies"`
+}
+
+// JobState is the phase of a Job's lifecycle.
+type JobState string
+
+const (
+	// Pending is the phase that the Job is pending in the queue, waiting to
+	// be scheduled.
+	Pending JobState = "Pending"
+	// Aborting is the phase that the Job is aborting.
+	Aborting JobState = "Aborting"
+	// Aborted is the phase that the Job is aborted.
+	Aborted JobState = "Aborted"
+	// Running is the phase that minimal available tasks of the Job are
+	// running.
+	Running JobState = "Running"
+	// Restarting is the phase that the Job is restarting.
+	Restarting JobState = "Restarting"
+	// Completing is the phase that the Job is completing.
+	Completing JobState = "Completing"
+	// Completed is the phase that the Job is completed.
+	Completed JobState = "Completed"
+	// Terminating is the phase that the Job is terminating.
+	Terminating JobState = "Terminating"
+	// Terminated is the phase that the Job is terminated.
+	Terminated JobState = "Terminated"
+	// Failed is the phase that the Job is failed.
+	Failed JobState = "Failed"
+	// Inqueue is the phase that Job is enqueued.
+	Inqueue JobState = "Inqueue"
+)
+
+// JobCondition records the state transition history of a Job.
+type JobCondition struct {
+	// Status is the phase the Job was in when this condition was recorded.
+	Status JobState `json:"status,omitempty" protobuf:"bytes,1,opt,name=status"`
+
+	// LastTransitionTime is the last time this Job transitioned into Status.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty" protobuf:"bytes,2,opt,name=lastTransitionTime"`
+}
+
+// TaskState records how many Pods of a task are in each Pod phase.
+type TaskState struct {
+	// Phase holds the number of Pods currently in each v1.PodPhase.
+	// +optional
+	Phase map[v1.PodPhase]int32 `json:"phase,omitempty" protobuf:"bytes,1,rep,name=phase"`
+}
+
+// JobStatus represents the current state of a Job.
+type JobStatus struct {
+	// State is the overall phase of the Job.
+	// +optional
+	State JobState `json:"state,omitempty" protobuf:"bytes,1,opt,name=state"`
+
+	// MinAvailable is the current minimal available tasks to run the Job.
+	// +optional
+	MinAvailable int32 `json:"minAvailable,omitempty" protobuf:"varint,2,opt,name=minAvailable"`
+
+	// Pending is the number of Pods in the Pending phase.
+	// +optional
+	Pending int32 `json:"pending,omitempty" protobuf:"varint,3,opt,name=pending"`
+
+	// Running is the number of Pods in the Running phase.
+	// +optional
+	Running int32 `json:"running,omitempty" protobuf:"varint,4,opt,name=running"`
+
+	// Succeeded is the number of Pods that completed successfully.
+	// +optional
+	Succeeded int32 `json:"succeeded,omitempty" protobuf:"varint,5,opt,name=succeeded"`
+
+	// Failed is the number of Pods that terminated in failure.
+	// +optional
+	Failed int32 `json:"failed,omitempty" protobuf:"varint,6,opt,name=failed"`
+
+	// Terminating is the number of Pods currently terminating.
+	// +optional
+	Terminating int32 `json:"terminating,omitempty" protobuf:"varint,7,opt,name=terminating"`
+
+	// Unknown is the number of Pods whose phase could not be observed.
+	// +optional
+	Unknown int32 `json:"unknown,omitempty" protobuf:"varint,8,opt,name=unknown"`
+
+	// Version is bumped every time the Job spec is updated, to fence stale
+	// reconciles.
+	// +optional
+	Version int32 `json:"version,omitempty" protobuf:"varint,9,opt,name=version"`
+
+	// RetryCount is the number of times the Job has been restarted.
+	// +optional
+	RetryCount int32 `json:"retryCount,omitempty" protobuf:"varint,10,opt,name=retryCount"`
+
+	// Conditions is the state transition history of the Job.
+	// +optional
+	Conditions []JobCondition `json:"conditions,omitempty" protobuf:"bytes,11,rep,name=conditions"`
+
+	// TaskStatusCount holds, per task name, how many of that task's Pods are
+	// in each phase.
+	// +optional
+	TaskStatusCount map[string]TaskState `json:"taskStatusCount,omitempty" protobuf:"bytes,12,rep,name=taskStatusCount"`
+
+	// LifecyclePolicyStatuses holds one entry per trigger (an Event or a
+	// decimal ExitCode) whose Actions chain has started executing, so that a
+	// controller restart resumes each chain from its persisted step instead
+	// of starting over from the first step. Entries are keyed by trigger
+	// alone, so policies that share a trigger share one entry.
+	// +optional
+	LifecyclePolicyStatuses []LifecyclePolicyStatus `json:"lifecyclePolicyStatuses,omitempty" protobuf:"bytes,13,rep,name=lifecyclePolicyStatuses"`
+}
+
+// Event represent the phase of Job, and let Job set corresponding
+// policy according to this Event.
+type Event string
+
+const (
+	// AnyEvent means all event
+	AnyEvent Event = "*"
+	// PodFailedEvent is triggered if a Pod failed
+	PodFailedEvent Event = "PodFailed"
+	// PodEvictedEvent is triggered if a Pod is evicted
+	PodEvictedEvent Event = "PodEvicted"
+	// JobUnknownEvent means the Job is in Unknown state
+	JobUnknownEvent Event = "JobUnknown"
+	// TaskCompletedEvent is triggered if a Task is completed
+	TaskCompletedEvent Event = "TaskCompleted"
+	// OutOfSyncEvent is triggered if a Job/Task fails to sync to etcd/api-server
+	OutOfSyncEvent Event = "OutOfSync"
+	// CommandIssuedEvent is triggered if a command is raised by a user
+	CommandIssuedEvent Event = "CommandIssued"
+)
+
+// Action is the action that Job controller will take according to the event.
+type Action string
+
+const (
+	// AbortJobAction if this action is set, the Job will be aborted
+	AbortJobAction Action = "AbortJob"
+	// RestartJobAction if this action is set, the Job will be restarted
+	RestartJobAction Action = "RestartJob"
+	// RestartTaskAction if this action is set, only the task will be restarted
+	RestartTaskAction Action = "RestartTask"
+	// TerminateJobAction if this action is set, the Job will be terminated
+	TerminateJobAction Action = "TerminateJob"
+	// CompleteJobAction if this action is set, the Job will be completed
+	CompleteJobAction Action = "CompleteJob"
+	// ResumeJobAction if this action is set, the Job will be resumed
+	ResumeJobAction Action = "ResumeJob"
+	// SyncJobAction if this action is set, the Job will be synchronized
+	SyncJobAction Action = "SyncJob"
+	// EnqueueAction if this action is set, the Job will be enqueued
+	EnqueueAction Action = "EnqueueAction"
+)
+
+// LifecyclePolicy specifies the lifecycle and relevant action against the Job
+// or Task.
+type LifecyclePolicy struct {
+	// The action that will be taken to the PodGroup according to Event.
+	// One of "AbortJob", "RestartJob", "RestartTask", "TerminateJob",
+	// "CompleteJob" or "ResumeJob". Mutually exclusive with Actions; exactly
+	// one of Action and Actions must be set.
+	// +optional
+	Action Action `json:"action,omitempty" protobuf:"bytes,1,opt,name=action"`
+
+	// Actions is an ordered chain of steps to take instead of a single
+	// Action, e.g. retry a few times before giving up and aborting the Job.
+	// Mutually exclusive with Action.
+	// +optional
+	Actions []ActionSpec `json:"actions,omitempty" protobuf:"bytes,8,rep,name=actions"`
+
+	// The Event recorded by the PodGroup. At any time, only one event can occur.
+	// +optional
+	Event Event `json:"event,omitempty" protobuf:"bytes,2,opt,name=event"`
+
+	// The Events recorded by the PodGroup. At any time, multiple events can occur.
+	// +optional
+	Events []Event `json:"events,omitempty" protobuf:"bytes,5,opt,name=events"`
+
+	// ExceptEvents is the complement of Event/Events: the Action applies to
+	// every allowed event except the ones listed here, e.g. "any event
+	// except TaskCompleted". Mutually exclusive with Event and Events.
+	// +optional
+	ExceptEvents []Event `json:"exceptEvents,omitempty" protobuf:"bytes,9,rep,name=exceptEvents"`
+
+	// The ExitCode of the Pod's container, on which the Action is taken.
+	// Mutually exclusive with ExitCodeRanges and ExitCodeGroup.
+	// +optional
+	ExitCode *int32 `json:"exitCode,omitempty" protobuf:"bytes,3,opt,name=exitCode"`
+
+	// ExitCodeRanges is a list of exit code ranges/sets the Action applies
+	// to, e.g. "1-125", "137,139,143" or "!0" to match every non-zero exit
+	// code. Mutually exclusive with ExitCode and ExitCodeGroup.
+	// +optional
+	ExitCodeRanges []string `json:"exitCodeRanges,omitempty" protobuf:"bytes,6,rep,name=exitCodeRanges"`
+
+	// ExitCodeGroup is a named shorthand for a common exit code pattern, one
+	// of "NonZero", "OOMKilled" or "Signal". Mutually exclusive with
+	// ExitCode and ExitCodeRanges.
+	// +optional
+	ExitCodeGroup ExitCodeGroup `json:"exitCodeGroup,omitempty" protobuf:"bytes,7,opt,name=exitCodeGroup"`
+
+	// Timeout is the grace period for controller to take action.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty" protobuf:"bytes,4,opt,name=timeout"`
+}
+
+// ExitCodeGroup is a named group of exit codes that can be used in place of
+// an explicit ExitCodeRanges entry.
+type ExitCodeGroup string
+
+const (
+	// NonZeroExitCodeGroup matches every non-zero exit code.
+	NonZeroExitCodeGroup ExitCodeGroup = "NonZero"
+	// OOMKilledExitCodeGroup matches the exit code Kubernetes reports for a
+	// container killed by the OOM killer (137).
+	OOMKilledExitCodeGroup ExitCodeGroup = "OOMKilled"
+	// SignalExitCodeGroup matches the exit codes reserved for a container
+	// terminated by a signal (128+signal, i.e. 129-159).
+	SignalExitCodeGroup ExitCodeGroup = "Signal"
+)
+
+// ActionSpec is a single step of a LifecyclePolicy's action chain.
+type ActionSpec struct {
+	// Action to take for this step.
+	Action Action `json:"action" protobuf:"bytes,1,opt,name=action"`
+
+	// MaxRetries bounds how many times this step is retried before the
+	// chain advances to the next step. Only meaningful for the
+	// RestartJob/RestartTask actions.
+	// +optional
+	MaxRetries int32 `json:"maxRetries,omitempty" protobuf:"varint,2,opt,name=maxRetries"`
+
+	// BackoffSeconds is the delay before this step is attempted, and before
+	// each of its retries.
+	// +optional
+	BackoffSeconds int64 `json:"backoffSeconds,omitempty" protobuf:"varint,3,opt,name=backoffSeconds"`
+
+	// TimeoutSeconds bounds how long this step is allowed to run before the
+	// chain advances to the next step.
+	// +optional
+	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty" protobuf:"varint,4,opt,name=timeoutSeconds"`
+}
+
+// LifecyclePolicyStatus records which step of a LifecyclePolicy's Actions
+// chain is currently active for a given trigger, so that a controller
+// restart resumes the chain instead of starting it over from the first
+// step. It is persisted in JobStatus.LifecyclePolicyStatuses.
+type LifecyclePolicyStatus struct {
+	// Event is the event, or the decimal ExitCode, that triggered this
+	// chain.
+	Event string `json:"event,omitempty" protobuf:"bytes,1,opt,name=event"`
+
+	// CurrentStep is the index, within Actions, of the step currently being
+	// executed or retried.
+	CurrentStep int32 `json:"currentStep" protobuf:"varint,2,opt,name=currentStep"`
+
+	// Retries is how many times CurrentStep has been retried so far.
+	// +optional
+	Retries int32 `json:"retries,omitempty" protobuf:"varint,3,opt,name=retries"`
+
+	// LastPodUID is the UID of the Pod whose occurrence of Event most
+	// recently advanced or retried this chain. It distinguishes a genuinely
+	// new occurrence of Event from the controller re-observing the same
+	// Pod's terminal state again, e.g. after a restart, which must not
+	// consume another retry or advance the chain a second time.
+	// +optional
+	LastPodUID string `json:"lastPodUID,omitempty" protobuf:"bytes,4,opt,name=lastPodUID"`
+}
+
+// VolumeSpec defines the specification of Volume, e.g. PVC.
+type VolumeSpec struct {
+	// Path within the container at which the volume should be mounted. Must
+	// not contain ':'.
+	MountPath string `json:"mountPath,omitempty" protobuf:"bytes,1,opt,name=mountPath"`
+
+	// defined the PVC name
+	// +optional
+	VolumeClaimName string `json:"volumeClaimName,omitempty" protobuf:"bytes,2,opt,name=volumeClaimName"`
+
+	// VolumeClaim defines the PVC used by the VolumeMount.
+	// +optional
+	VolumeClaim *v1.PersistentVolumeClaimSpec `json:"volumeClaim,omitempty" protobuf:"bytes,3,opt,name=volumeClaim"`
+
+	// VolumeSource supports mounting any other Kubernetes-native volume
+	// source, e.g. EmptyDir, ConfigMap, Secret, HostPath or a CSI ephemeral
+	// inline volume. Exactly one of VolumeClaim, VolumeClaimName or
+	// VolumeSource must be set.
+	// +optional
+	VolumeSource *v1.VolumeSource `json:"volumeSource,omitempty" protobuf:"bytes,4,opt,name=volumeSource"`
+
+	// ReadOnly mounts the volume read-only. Must be true when VolumeSource
+	// is a ConfigMap or Secret, since those are never writable.
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty" protobuf:"varint,5,opt,name=readOnly"`
+}
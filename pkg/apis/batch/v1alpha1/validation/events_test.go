@@ -0,0 +1,157 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	batchv1alpha1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+func TestExpandEventSet(t *testing.T) {
+	cases := []struct {
+		name    string
+		policy  batchv1alpha1.LifecyclePolicy
+		want    []batchv1alpha1.Event
+		wantErr bool
+	}{
+		{
+			name:   "single event",
+			policy: batchv1alpha1.LifecyclePolicy{Event: batchv1alpha1.PodFailedEvent},
+			want:   []batchv1alpha1.Event{batchv1alpha1.PodFailedEvent},
+		},
+		{
+			name:   "AnyEvent expands to every allowed event",
+			policy: batchv1alpha1.LifecyclePolicy{Event: batchv1alpha1.AnyEvent},
+			want: []batchv1alpha1.Event{
+				batchv1alpha1.JobUnknownEvent,
+				batchv1alpha1.PodEvictedEvent,
+				batchv1alpha1.PodFailedEvent,
+				batchv1alpha1.TaskCompletedEvent,
+			},
+		},
+		{
+			name:   "ExceptEvents is the complement of the allowed event set",
+			policy: batchv1alpha1.LifecyclePolicy{ExceptEvents: []batchv1alpha1.Event{batchv1alpha1.TaskCompletedEvent}},
+			want: []batchv1alpha1.Event{
+				batchv1alpha1.JobUnknownEvent,
+				batchv1alpha1.PodEvictedEvent,
+				batchv1alpha1.PodFailedEvent,
+			},
+		},
+		{
+			name:    "an event not allowed externally is an error",
+			policy:  batchv1alpha1.LifecyclePolicy{Event: batchv1alpha1.OutOfSyncEvent},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			set, errs := expandEventSet(tc.policy, field.NewPath("policies").Index(0))
+			if tc.wantErr {
+				if len(errs) == 0 {
+					t.Fatalf("expandEventSet(%+v) = %v, want error", tc.policy, set)
+				}
+				return
+			}
+			if len(errs) != 0 {
+				t.Fatalf("expandEventSet(%+v) returned unexpected errors: %v", tc.policy, errs)
+			}
+			if !equalEventSlices(sortedEvents(set), tc.want) {
+				t.Errorf("expandEventSet(%+v) = %v, want %v", tc.policy, sortedEvents(set), tc.want)
+			}
+		})
+	}
+}
+
+func equalEventSlices(a, b []batchv1alpha1.Event) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestValidatePoliciesEventOverlap(t *testing.T) {
+	cases := []struct {
+		name     string
+		policies []batchv1alpha1.LifecyclePolicy
+		wantErrs int
+	}{
+		{
+			name: "disjoint single events do not overlap",
+			policies: []batchv1alpha1.LifecyclePolicy{
+				{Action: batchv1alpha1.RestartTaskAction, Event: batchv1alpha1.PodFailedEvent},
+				{Action: batchv1alpha1.AbortJobAction, Event: batchv1alpha1.JobUnknownEvent},
+			},
+		},
+		{
+			name: "AnyEvent overlaps with every other event-based policy",
+			policies: []batchv1alpha1.LifecyclePolicy{
+				{Action: batchv1alpha1.RestartTaskAction, Event: batchv1alpha1.AnyEvent},
+				{Action: batchv1alpha1.AbortJobAction, Event: batchv1alpha1.PodFailedEvent},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "ExceptEvents overlaps with an explicit event still in its complement",
+			policies: []batchv1alpha1.LifecyclePolicy{
+				{Action: batchv1alpha1.RestartTaskAction, ExceptEvents: []batchv1alpha1.Event{batchv1alpha1.TaskCompletedEvent}},
+				{Action: batchv1alpha1.AbortJobAction, Event: batchv1alpha1.PodFailedEvent},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "two ExceptEvents policies excluding different events still overlap on the events neither excludes",
+			policies: []batchv1alpha1.LifecyclePolicy{
+				{Action: batchv1alpha1.RestartTaskAction, ExceptEvents: []batchv1alpha1.Event{batchv1alpha1.TaskCompletedEvent}},
+				{Action: batchv1alpha1.AbortJobAction, ExceptEvents: []batchv1alpha1.Event{batchv1alpha1.JobUnknownEvent}},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "a policy excluding every allowed event but one does not overlap with an event it excludes",
+			policies: []batchv1alpha1.LifecyclePolicy{
+				{
+					Action: batchv1alpha1.RestartTaskAction,
+					ExceptEvents: []batchv1alpha1.Event{
+						batchv1alpha1.JobUnknownEvent,
+						batchv1alpha1.PodEvictedEvent,
+						batchv1alpha1.TaskCompletedEvent,
+					},
+				},
+				{Action: batchv1alpha1.AbortJobAction, Event: batchv1alpha1.JobUnknownEvent},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidatePolicies(tc.policies, field.NewPath("spec", "policies"), nil)
+			if len(errs) != tc.wantErrs {
+				t.Errorf("ValidatePolicies(%+v) = %v errors, want %d: %v", tc.policies, len(errs), tc.wantErrs, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	batchv1alpha1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+// minExitCode and maxExitCode bound the exit codes a container can report.
+const (
+	minExitCode = 0
+	maxExitCode = 255
+)
+
+// exitCodeGroupRanges expands the named ExitCodeGroup shorthands into their
+// equivalent ExitCodeRanges entry.
+var exitCodeGroupRanges = map[batchv1alpha1.ExitCodeGroup]string{
+	batchv1alpha1.NonZeroExitCodeGroup:   "!0",
+	batchv1alpha1.OOMKilledExitCodeGroup: "137",
+	batchv1alpha1.SignalExitCodeGroup:    "129-159",
+}
+
+// exitCodeSet is the canonical, fully-expanded form of a policy's exit code
+// matchers: the set of exit codes it applies to.
+type exitCodeSet map[int32]struct{}
+
+// canonicalizeExitCodeRanges expands a LifecyclePolicy's ExitCode,
+// ExitCodeRanges and ExitCodeGroup into a single exitCodeSet.
+func canonicalizeExitCodeRanges(policy batchv1alpha1.LifecyclePolicy) (exitCodeSet, error) {
+	var entries []string
+	if policy.ExitCode != nil {
+		entries = append(entries, strconv.Itoa(int(*policy.ExitCode)))
+	}
+	entries = append(entries, policy.ExitCodeRanges...)
+	if policy.ExitCodeGroup != "" {
+		group, ok := exitCodeGroupRanges[policy.ExitCodeGroup]
+		if !ok {
+			return nil, fmt.Errorf("unknown exitCodeGroup %q", policy.ExitCodeGroup)
+		}
+		entries = append(entries, group)
+	}
+
+	set := exitCodeSet{}
+	for _, entry := range entries {
+		entrySet, err := parseExitCodeEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		for code := range entrySet {
+			set[code] = struct{}{}
+		}
+	}
+	return set, nil
+}
+
+// parseExitCodeEntry parses a single ExitCodeRanges element, e.g. "1-125",
+// "137,139,143" or "!0", into the set of exit codes it matches.
+func parseExitCodeEntry(entry string) (exitCodeSet, error) {
+	var includes, excludes []string
+	for _, term := range strings.Split(entry, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		if strings.HasPrefix(term, "!") {
+			excludes = append(excludes, strings.TrimPrefix(term, "!"))
+		} else {
+			includes = append(includes, term)
+		}
+	}
+
+	include := exitCodeSet{}
+	if len(includes) == 0 && len(excludes) > 0 {
+		for code := minExitCode; code <= maxExitCode; code++ {
+			include[int32(code)] = struct{}{}
+		}
+	}
+	for _, term := range includes {
+		termSet, err := parseExitCodeTerm(term)
+		if err != nil {
+			return nil, err
+		}
+		for code := range termSet {
+			include[code] = struct{}{}
+		}
+	}
+
+	for _, term := range excludes {
+		termSet, err := parseExitCodeTerm(term)
+		if err != nil {
+			return nil, err
+		}
+		for code := range termSet {
+			delete(include, code)
+		}
+	}
+
+	return include, nil
+}
+
+// parseExitCodeTerm parses a single term of an entry, either a bare exit
+// code ("137") or an inclusive range ("1-125").
+func parseExitCodeTerm(term string) (exitCodeSet, error) {
+	low, high, found := strings.Cut(term, "-")
+	if !found {
+		code, err := strconv.Atoi(term)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exit code %q: %v", term, err)
+		}
+		if code < minExitCode || code > maxExitCode {
+			return nil, fmt.Errorf("exit code %d is out of range [%d, %d]", code, minExitCode, maxExitCode)
+		}
+		return exitCodeSet{int32(code): {}}, nil
+	}
+
+	lowCode, err := strconv.Atoi(low)
+	if err != nil {
+		return nil, fmt.Errorf("invalid range %q: %v", term, err)
+	}
+	highCode, err := strconv.Atoi(high)
+	if err != nil {
+		return nil, fmt.Errorf("invalid range %q: %v", term, err)
+	}
+	if lowCode < minExitCode || highCode > maxExitCode || lowCode > highCode {
+		return nil, fmt.Errorf("invalid range %q: must satisfy %d <= low <= high <= %d", term, minExitCode, maxExitCode)
+	}
+
+	set := exitCodeSet{}
+	for code := lowCode; code <= highCode; code++ {
+		set[int32(code)] = struct{}{}
+	}
+	return set, nil
+}
+
+// MatchExitCodePolicy returns the first policy, in list order, whose exit
+// code matchers cover exitCode. A policy with a bare ExitCode takes
+// precedence over one that only matches via ExitCodeRanges/ExitCodeGroup,
+// even if the latter appears earlier in the list.
+func MatchExitCodePolicy(policies []batchv1alpha1.LifecyclePolicy, exitCode int32) *batchv1alpha1.LifecyclePolicy {
+	var rangeMatch *batchv1alpha1.LifecyclePolicy
+	for i := range policies {
+		policy := &policies[i]
+		if policy.ExitCode == nil && len(policy.ExitCodeRanges) == 0 && policy.ExitCodeGroup == "" {
+			continue
+		}
+		if policy.ExitCode != nil && *policy.ExitCode == exitCode {
+			return policy
+		}
+		if rangeMatch != nil {
+			continue
+		}
+		set, err := canonicalizeExitCodeRanges(*policy)
+		if err != nil {
+			continue
+		}
+		if _, ok := set[exitCode]; ok {
+			rangeMatch = policy
+		}
+	}
+	return rangeMatch
+}
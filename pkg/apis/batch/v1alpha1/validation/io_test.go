@@ -0,0 +1,223 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	batchv1alpha1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+func TestValidateVolumeSource(t *testing.T) {
+	negativeSize := resource.MustParse("-1Gi")
+
+	cases := []struct {
+		name     string
+		source   v1.VolumeSource
+		readOnly bool
+		wantErrs int
+	}{
+		{
+			name:   "emptyDir with default medium",
+			source: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+		},
+		{
+			name:   "emptyDir with memory medium",
+			source: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{Medium: v1.StorageMediumMemory}},
+		},
+		{
+			name:     "emptyDir with unsupported medium",
+			source:   v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{Medium: v1.StorageMedium("HugePages")}},
+			wantErrs: 1,
+		},
+		{
+			name:     "emptyDir with negative sizeLimit",
+			source:   v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{SizeLimit: &negativeSize}},
+			wantErrs: 1,
+		},
+		{
+			name:     "configMap without a name",
+			source:   v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{}},
+			readOnly: true,
+			wantErrs: 1,
+		},
+		{
+			name: "configMap not marked readOnly",
+			source: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{
+				LocalObjectReference: v1.LocalObjectReference{Name: "cfg"},
+			}},
+			readOnly: false,
+			wantErrs: 1,
+		},
+		{
+			name: "valid configMap",
+			source: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{
+				LocalObjectReference: v1.LocalObjectReference{Name: "cfg"},
+			}},
+			readOnly: true,
+		},
+		{
+			name:     "secret without a secretName",
+			source:   v1.VolumeSource{Secret: &v1.SecretVolumeSource{}},
+			readOnly: true,
+			wantErrs: 1,
+		},
+		{
+			name:     "secret not marked readOnly",
+			source:   v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "sec"}},
+			readOnly: false,
+			wantErrs: 1,
+		},
+		{
+			name:     "valid secret",
+			source:   v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "sec"}},
+			readOnly: true,
+		},
+		{
+			name:     "hostPath without a path",
+			source:   v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{}},
+			wantErrs: 1,
+		},
+		{
+			name:     "hostPath with a relative path",
+			source:   v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "relative/path"}},
+			wantErrs: 1,
+		},
+		{
+			name:     "hostPath with backsteps",
+			source:   v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/data/../etc"}},
+			wantErrs: 1,
+		},
+		{
+			name:   "valid hostPath",
+			source: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/data"}},
+		},
+		{
+			name:     "csi without a driver",
+			source:   v1.VolumeSource{CSI: &v1.CSIVolumeSource{}},
+			wantErrs: 1,
+		},
+		{
+			name:   "valid csi",
+			source: v1.VolumeSource{CSI: &v1.CSIVolumeSource{Driver: "csi.example.com"}},
+		},
+		{
+			name:     "no source set",
+			source:   v1.VolumeSource{},
+			wantErrs: 1,
+		},
+		{
+			name: "more than one source set",
+			source: v1.VolumeSource{
+				EmptyDir: &v1.EmptyDirVolumeSource{},
+				HostPath: &v1.HostPathVolumeSource{Path: "/data"},
+			},
+			wantErrs: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := tc.source
+			errs := validateVolumeSource(&source, tc.readOnly, field.NewPath("volumeSource"))
+			if len(errs) != tc.wantErrs {
+				t.Errorf("validateVolumeSource(%+v, readOnly=%v) = %v errors, want %d: %v", tc.source, tc.readOnly, len(errs), tc.wantErrs, errs)
+			}
+		})
+	}
+}
+
+func TestValidateIO(t *testing.T) {
+	cases := []struct {
+		name     string
+		volumes  []batchv1alpha1.VolumeSpec
+		wantErrs int
+	}{
+		{
+			name: "valid volumeClaimName",
+			volumes: []batchv1alpha1.VolumeSpec{
+				{MountPath: "/data", VolumeClaimName: "my-pvc"},
+			},
+		},
+		{
+			name: "valid volumeSource",
+			volumes: []batchv1alpha1.VolumeSpec{
+				{
+					MountPath:    "/config",
+					ReadOnly:     true,
+					VolumeSource: &v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: "cfg"}}},
+				},
+			},
+		},
+		{
+			name: "missing mountPath",
+			volumes: []batchv1alpha1.VolumeSpec{
+				{VolumeClaimName: "my-pvc"},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "relative mountPath",
+			volumes: []batchv1alpha1.VolumeSpec{
+				{MountPath: "data", VolumeClaimName: "my-pvc"},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "reserved mountPath",
+			volumes: []batchv1alpha1.VolumeSpec{
+				{MountPath: "/etc/volcano/config", VolumeClaimName: "my-pvc"},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "duplicate mountPath",
+			volumes: []batchv1alpha1.VolumeSpec{
+				{MountPath: "/data", VolumeClaimName: "pvc-a"},
+				{MountPath: "/data", VolumeClaimName: "pvc-b"},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "no volume source specified",
+			volumes: []batchv1alpha1.VolumeSpec{
+				{MountPath: "/data"},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "more than one volume source specified",
+			volumes: []batchv1alpha1.VolumeSpec{
+				{MountPath: "/data", VolumeClaimName: "my-pvc", VolumeClaim: &v1.PersistentVolumeClaimSpec{}},
+			},
+			wantErrs: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateIO(tc.volumes, field.NewPath("spec", "volumes"))
+			if len(errs) != tc.wantErrs {
+				t.Errorf("ValidateIO(%+v) = %v errors, want %d: %v", tc.volumes, len(errs), tc.wantErrs, errs)
+			}
+		})
+	}
+}
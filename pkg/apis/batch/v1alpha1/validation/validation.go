@@ -0,0 +1,530 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validation holds the validation logic for the batch/v1alpha1 API
+// group. It is consumed both by the admission webhook and by the Job
+// controller's reconcile path, so that a Job can never be admitted or
+// reconciled with an invalid lifecycle policy or volume spec, regardless of
+// whether it entered the cluster through the webhook, `--validate=false`, or
+// a GitOps sync with the webhook unreachable.
+package validation
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kubernetes/pkg/apis/core/validation"
+
+	batchv1alpha1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+// policyEventMap defines all policy events and whether to allow external use.
+var policyEventMap = map[batchv1alpha1.Event]bool{
+	batchv1alpha1.AnyEvent:           true,
+	batchv1alpha1.PodFailedEvent:     true,
+	batchv1alpha1.PodEvictedEvent:    true,
+	batchv1alpha1.JobUnknownEvent:    true,
+	batchv1alpha1.TaskCompletedEvent: true,
+	batchv1alpha1.OutOfSyncEvent:     false,
+	batchv1alpha1.CommandIssuedEvent: false,
+}
+
+// policyActionMap defines all policy actions and whether to allow external use.
+var policyActionMap = map[batchv1alpha1.Action]bool{
+	batchv1alpha1.AbortJobAction:     true,
+	batchv1alpha1.RestartJobAction:   true,
+	batchv1alpha1.RestartTaskAction:  true,
+	batchv1alpha1.TerminateJobAction: true,
+	batchv1alpha1.CompleteJobAction:  true,
+	batchv1alpha1.ResumeJobAction:    true,
+	batchv1alpha1.SyncJobAction:      false,
+	batchv1alpha1.EnqueueAction:      false,
+}
+
+// ValidatePolicies validates the lifecycle policies of a Job or TaskSpec and
+// returns every violation found, rooted at fldPath. activeDeadlineSeconds is
+// the owning Job's ActiveDeadlineSeconds, if any, against which an action
+// chain's total backoff is checked; pass nil when the Job does not set one.
+func ValidatePolicies(policies []batchv1alpha1.LifecyclePolicy, fldPath *field.Path, activeDeadlineSeconds *int64) field.ErrorList {
+	var errs field.ErrorList
+	// bareExitCodeOwners and rangeExitCodeOwners are tracked separately: a
+	// bare ExitCode is allowed to fall inside another policy's
+	// ExitCodeRanges/ExitCodeGroup, since MatchExitCodePolicy deterministically
+	// prefers the bare ExitCode match. Only overlap within the same kind
+	// (bare-vs-bare, range-vs-range) is ambiguous and therefore rejected.
+	bareExitCodeOwners := map[int32]struct{}{}
+	rangeExitCodeOwners := map[int32]struct{}{}
+
+	// eventSets[index] is the fully-expanded set of events policies[index]
+	// applies to; it is populated below and then checked pairwise for
+	// overlap once every policy's own rules have been validated, so that an
+	// early continue for one policy never masks an overlap involving
+	// policies that come after it.
+	eventSets := make([]map[batchv1alpha1.Event]struct{}, len(policies))
+
+	for index, policy := range policies {
+		idxPath := fldPath.Index(index)
+		hasExitCodeMatcher := policy.ExitCode != nil || len(policy.ExitCodeRanges) != 0 || policy.ExitCodeGroup != ""
+		hasEventMatcher := policy.Event != "" || len(policy.Events) != 0 || len(policy.ExceptEvents) != 0
+
+		if policy.Action != "" && len(policy.Actions) != 0 {
+			errs = append(errs, field.Invalid(idxPath, policy, "must not specify action together with actions"))
+			continue
+		}
+		errs = append(errs, validatePolicyAction(policy, idxPath, activeDeadlineSeconds)...)
+
+		if policy.ExitCode != nil && (len(policy.ExitCodeRanges) != 0 || policy.ExitCodeGroup != "") {
+			errs = append(errs, field.Invalid(idxPath, policy, "must not specify exitCode together with exitCodeRanges/exitCodeGroup"))
+			continue
+		}
+		if len(policy.ExitCodeRanges) != 0 && policy.ExitCodeGroup != "" {
+			errs = append(errs, field.Invalid(idxPath, policy, "must not specify exitCodeRanges together with exitCodeGroup"))
+			continue
+		}
+		if (policy.Event != "" || len(policy.Events) != 0) && len(policy.ExceptEvents) != 0 {
+			errs = append(errs, field.Invalid(idxPath, policy, "must not specify exceptEvents together with event/events"))
+			continue
+		}
+
+		if hasEventMatcher && hasExitCodeMatcher {
+			errs = append(errs, field.Invalid(idxPath, policy, "must not specify event/exceptEvents and exitCode/exitCodeRanges/exitCodeGroup simultaneously"))
+			continue
+		}
+
+		if !hasEventMatcher && !hasExitCodeMatcher {
+			errs = append(errs, field.Invalid(idxPath, policy, "either event/exceptEvents or exitCode/exitCodeRanges/exitCodeGroup should be specified"))
+			continue
+		}
+
+		if hasEventMatcher {
+			set, err := expandEventSet(policy, idxPath)
+			if err != nil {
+				errs = append(errs, err...)
+				continue
+			}
+			eventSets[index] = set
+			continue
+		}
+
+		if policy.ExitCode != nil && *policy.ExitCode == 0 {
+			errs = append(errs, field.Invalid(idxPath.Child("exitCode"), *policy.ExitCode, "0 is not a valid error code"))
+			continue
+		}
+
+		if policy.ExitCode != nil {
+			code := *policy.ExitCode
+			if _, found := bareExitCodeOwners[code]; found {
+				errs = append(errs, field.Duplicate(idxPath.Child("exitCode"), code))
+				continue
+			}
+			bareExitCodeOwners[code] = struct{}{}
+			continue
+		}
+
+		exitCodeSetPath := idxPath.Child("exitCodeRanges")
+		if policy.ExitCodeGroup != "" {
+			exitCodeSetPath = idxPath.Child("exitCodeGroup")
+		}
+
+		set, err := canonicalizeExitCodeRanges(policy)
+		if err != nil {
+			errs = append(errs, field.Invalid(exitCodeSetPath, policy.ExitCodeRanges, err.Error()))
+			continue
+		}
+		if _, covers0 := set[0]; covers0 {
+			errs = append(errs, field.Invalid(exitCodeSetPath, policy.ExitCodeRanges,
+				"range covers exit code 0; exclude it explicitly, e.g. with \"!0\""))
+			continue
+		}
+
+		overlapping := map[int32]struct{}{}
+		for code := range set {
+			if _, found := rangeExitCodeOwners[code]; found {
+				overlapping[code] = struct{}{}
+			}
+		}
+		if len(overlapping) > 0 {
+			errs = append(errs, field.Invalid(exitCodeSetPath, policy.ExitCodeRanges,
+				fmt.Sprintf("overlaps with another policy's exitCodeRanges/exitCodeGroup on exit code(s) %v", sortedExitCodes(overlapping))))
+			continue
+		}
+		for code := range set {
+			rangeExitCodeOwners[code] = struct{}{}
+		}
+	}
+
+	errs = append(errs, checkEventSetOverlap(eventSets, fldPath)...)
+
+	return errs
+}
+
+// expandEventSet expands a policy's Event/Events/ExceptEvents into the
+// concrete, fully-enumerated set of events it applies to. AnyEvent and
+// ExceptEvents are both expanded against the set of externally-allowed
+// events, so overlap detection never has to special-case either form.
+func expandEventSet(policy batchv1alpha1.LifecyclePolicy, fldPath *field.Path) (map[batchv1alpha1.Event]struct{}, field.ErrorList) {
+	if len(policy.ExceptEvents) != 0 {
+		set := map[batchv1alpha1.Event]struct{}{}
+		for event := range policyEventMap {
+			if policyEventMap[event] && event != batchv1alpha1.AnyEvent {
+				set[event] = struct{}{}
+			}
+		}
+		var errs field.ErrorList
+		for _, event := range policy.ExceptEvents {
+			if allow, ok := policyEventMap[event]; !ok || !allow {
+				errs = append(errs, field.Invalid(fldPath.Child("exceptEvents"), event, "invalid policy event"))
+				continue
+			}
+			delete(set, event)
+		}
+		return set, errs
+	}
+
+	var errs field.ErrorList
+	set := map[batchv1alpha1.Event]struct{}{}
+	for _, event := range getEventList(policy) {
+		if allow, ok := policyEventMap[event]; !ok || !allow {
+			errs = append(errs, field.Invalid(fldPath.Child("event"), event, "invalid policy event"))
+			continue
+		}
+		if event == batchv1alpha1.AnyEvent {
+			for e := range policyEventMap {
+				if policyEventMap[e] && e != batchv1alpha1.AnyEvent {
+					set[e] = struct{}{}
+				}
+			}
+			continue
+		}
+		set[event] = struct{}{}
+	}
+	return set, errs
+}
+
+// checkEventSetOverlap reports every pair of policies whose expanded event
+// sets intersect, including intersections introduced by AnyEvent or
+// ExceptEvents.
+func checkEventSetOverlap(eventSets []map[batchv1alpha1.Event]struct{}, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for i := 0; i < len(eventSets); i++ {
+		if eventSets[i] == nil {
+			continue
+		}
+		for j := i + 1; j < len(eventSets); j++ {
+			if eventSets[j] == nil {
+				continue
+			}
+			intersection := map[batchv1alpha1.Event]struct{}{}
+			for event := range eventSets[i] {
+				if _, found := eventSets[j][event]; found {
+					intersection[event] = struct{}{}
+				}
+			}
+			if len(intersection) > 0 {
+				errs = append(errs, field.Invalid(fldPath.Index(j), eventSets[j],
+					fmt.Sprintf("overlaps with policy at index %d on event(s) %v", i, sortedEvents(intersection))))
+			}
+		}
+	}
+	return errs
+}
+
+func sortedEvents(set map[batchv1alpha1.Event]struct{}) []batchv1alpha1.Event {
+	events := make([]batchv1alpha1.Event, 0, len(set))
+	for event := range set {
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i] < events[j] })
+	return events
+}
+
+// validatePolicyAction validates the "what to do" side of a policy: either a
+// single Action or an Actions chain, never both.
+func validatePolicyAction(policy batchv1alpha1.LifecyclePolicy, fldPath *field.Path, activeDeadlineSeconds *int64) field.ErrorList {
+	if len(policy.Actions) != 0 {
+		return validateActionChain(policy.Actions, activeDeadlineSeconds, fldPath.Child("actions"))
+	}
+	if allow, ok := policyActionMap[policy.Action]; !ok || !allow {
+		return field.ErrorList{field.Invalid(fldPath.Child("action"), policy.Action, "invalid policy action")}
+	}
+	return nil
+}
+
+// validateActionChain validates an ordered Actions chain: only the last step
+// may be a terminal action, MaxRetries is only meaningful on Restart*
+// actions, and the sum of BackoffSeconds must fit within the Job's
+// ActiveDeadlineSeconds when one is set.
+func validateActionChain(actions []batchv1alpha1.ActionSpec, activeDeadlineSeconds *int64, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	var backoffSum int64
+
+	for index, action := range actions {
+		idxPath := fldPath.Index(index)
+
+		if allow, ok := policyActionMap[action.Action]; !ok || !allow {
+			errs = append(errs, field.Invalid(idxPath.Child("action"), action.Action, "invalid policy action"))
+			continue
+		}
+		if isTerminalAction(action.Action) && index != len(actions)-1 {
+			errs = append(errs, field.Invalid(idxPath.Child("action"), action.Action,
+				"a terminal action (AbortJob, CompleteJob, TerminateJob) may only appear as the last step of the chain"))
+		}
+		if action.MaxRetries != 0 && !isRestartAction(action.Action) {
+			errs = append(errs, field.Invalid(idxPath.Child("maxRetries"), action.MaxRetries,
+				"maxRetries is only meaningful for RestartJob/RestartTask actions"))
+		}
+		if action.BackoffSeconds < 0 {
+			errs = append(errs, field.Invalid(idxPath.Child("backoffSeconds"), action.BackoffSeconds, "must be non-negative"))
+		} else {
+			backoffSum += action.BackoffSeconds
+		}
+	}
+
+	if activeDeadlineSeconds != nil && backoffSum > *activeDeadlineSeconds {
+		errs = append(errs, field.Invalid(fldPath, actions,
+			fmt.Sprintf("sum of backoffSeconds (%d) exceeds the Job's activeDeadlineSeconds (%d)", backoffSum, *activeDeadlineSeconds)))
+	}
+
+	return errs
+}
+
+func isTerminalAction(action batchv1alpha1.Action) bool {
+	switch action {
+	case batchv1alpha1.AbortJobAction, batchv1alpha1.CompleteJobAction, batchv1alpha1.TerminateJobAction:
+		return true
+	default:
+		return false
+	}
+}
+
+func isRestartAction(action batchv1alpha1.Action) bool {
+	switch action {
+	case batchv1alpha1.RestartJobAction, batchv1alpha1.RestartTaskAction:
+		return true
+	default:
+		return false
+	}
+}
+
+func sortedExitCodes(set map[int32]struct{}) []int32 {
+	codes := make([]int32, 0, len(set))
+	for code := range set {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
+
+func getEventList(policy batchv1alpha1.LifecyclePolicy) []batchv1alpha1.Event {
+	policyEventsList := policy.Events
+	if len(policy.Event) > 0 {
+		policyEventsList = append(policyEventsList, policy.Event)
+	}
+	return removeDuplicates(policyEventsList)
+}
+
+func removeDuplicates(eventList []batchv1alpha1.Event) []batchv1alpha1.Event {
+	keys := make(map[batchv1alpha1.Event]bool)
+	list := []batchv1alpha1.Event{}
+	for _, val := range eventList {
+		if _, value := keys[val]; !value {
+			keys[val] = true
+			list = append(list, val)
+		}
+	}
+	return list
+}
+
+// GetValidEvents returns the list of events that may be used externally in a
+// LifecyclePolicy.
+func GetValidEvents() []batchv1alpha1.Event {
+	var events []batchv1alpha1.Event
+	for e, allow := range policyEventMap {
+		if allow {
+			events = append(events, e)
+		}
+	}
+
+	return events
+}
+
+// GetValidActions returns the list of actions that may be used externally in
+// a LifecyclePolicy.
+func GetValidActions() []batchv1alpha1.Action {
+	var actions []batchv1alpha1.Action
+	for a, allow := range policyActionMap {
+		if allow {
+			actions = append(actions, a)
+		}
+	}
+
+	return actions
+}
+
+// reservedMountPaths are paths the Volcano executor injects into every task
+// container; a user-specified VolumeSpec must not claim them.
+var reservedMountPaths = []string{
+	"/etc/volcano",
+	"/etc/podinfo",
+}
+
+// ValidateIO validates the IO configuration of a Job's volumes, rooted at
+// fldPath.
+func ValidateIO(volumes []batchv1alpha1.VolumeSpec, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	volumeMap := map[string]bool{}
+	for index, volume := range volumes {
+		idxPath := fldPath.Index(index)
+
+		if len(volume.MountPath) == 0 {
+			errs = append(errs, field.Required(idxPath.Child("mountPath"), "mountPath is required"))
+			continue
+		}
+		if !path.IsAbs(volume.MountPath) {
+			errs = append(errs, field.Invalid(idxPath.Child("mountPath"), volume.MountPath, "must be an absolute path"))
+			continue
+		}
+		reserved := false
+		for _, reservedPath := range reservedMountPaths {
+			if volume.MountPath == reservedPath || strings.HasPrefix(volume.MountPath, reservedPath+"/") {
+				errs = append(errs, field.Invalid(idxPath.Child("mountPath"), volume.MountPath,
+					fmt.Sprintf("%s is reserved for the Volcano executor", reservedPath)))
+				reserved = true
+				break
+			}
+		}
+		if reserved {
+			continue
+		}
+		if _, found := volumeMap[volume.MountPath]; found {
+			errs = append(errs, field.Duplicate(idxPath.Child("mountPath"), volume.MountPath))
+			continue
+		}
+
+		sourceCount := 0
+		if volume.VolumeClaim != nil {
+			sourceCount++
+		}
+		if volume.VolumeClaimName != "" {
+			sourceCount++
+		}
+		if volume.VolumeSource != nil {
+			sourceCount++
+		}
+		if sourceCount == 0 {
+			errs = append(errs, field.Required(idxPath, "exactly one of VolumeClaim, VolumeClaimName or VolumeSource must be specified"))
+			continue
+		}
+		if sourceCount > 1 {
+			errs = append(errs, field.Invalid(idxPath, volume, "must not specify more than one of VolumeClaim, VolumeClaimName and VolumeSource"))
+			continue
+		}
+
+		if len(volume.VolumeClaimName) != 0 {
+			if errMsgs := validation.ValidatePersistentVolumeName(volume.VolumeClaimName, false); len(errMsgs) > 0 {
+				errs = append(errs, field.Invalid(idxPath.Child("volumeClaimName"), volume.VolumeClaimName,
+					fmt.Sprintf("invalid VolumeClaimName: %v", errMsgs)))
+				continue
+			}
+		}
+
+		if volume.VolumeSource != nil {
+			errs = append(errs, validateVolumeSource(volume.VolumeSource, volume.ReadOnly, idxPath.Child("volumeSource"))...)
+		}
+
+		volumeMap[volume.MountPath] = true
+	}
+	return errs
+}
+
+// validEmptyDirMedia are the storage media corev1.EmptyDirVolumeSource
+// accepts: the default (on-disk) medium, and tmpfs.
+var validEmptyDirMedia = map[v1.StorageMedium]bool{
+	v1.StorageMediumDefault: true,
+	v1.StorageMediumMemory:  true,
+}
+
+// validateVolumeSource validates the subset of corev1.VolumeSource Volcano
+// supports as scratch/config volumes: EmptyDir, ConfigMap, Secret, HostPath
+// and CSI ephemeral inline volumes. readOnly is the owning VolumeSpec's
+// ReadOnly setting, required for ConfigMap and Secret sources since neither
+// is ever writable.
+func validateVolumeSource(source *v1.VolumeSource, readOnly bool, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	set := 0
+
+	if source.EmptyDir != nil {
+		set++
+		if !validEmptyDirMedia[source.EmptyDir.Medium] {
+			errs = append(errs, field.NotSupported(fldPath.Child("emptyDir", "medium"), source.EmptyDir.Medium,
+				[]string{string(v1.StorageMediumDefault), string(v1.StorageMediumMemory)}))
+		}
+		if limit := source.EmptyDir.SizeLimit; limit != nil && limit.Sign() < 0 {
+			errs = append(errs, field.Invalid(fldPath.Child("emptyDir", "sizeLimit"), limit.String(), "must be non-negative"))
+		}
+	}
+	if source.ConfigMap != nil {
+		set++
+		if source.ConfigMap.Name == "" {
+			errs = append(errs, field.Required(fldPath.Child("configMap", "name"), "name is required"))
+		}
+		if !readOnly {
+			errs = append(errs, field.Invalid(fldPath.Child("configMap"), source.ConfigMap, "VolumeSpec.readOnly must be true for a configMap volumeSource"))
+		}
+	}
+	if source.Secret != nil {
+		set++
+		if source.Secret.SecretName == "" {
+			errs = append(errs, field.Required(fldPath.Child("secret", "secretName"), "secretName is required"))
+		}
+		if !readOnly {
+			errs = append(errs, field.Invalid(fldPath.Child("secret"), source.Secret, "VolumeSpec.readOnly must be true for a secret volumeSource"))
+		}
+	}
+	if source.HostPath != nil {
+		set++
+		if source.HostPath.Path == "" {
+			errs = append(errs, field.Required(fldPath.Child("hostPath", "path"), "path is required"))
+		} else if !path.IsAbs(source.HostPath.Path) {
+			errs = append(errs, field.Invalid(fldPath.Child("hostPath", "path"), source.HostPath.Path, "must be an absolute path"))
+		} else {
+			errs = append(errs, validation.ValidatePathNoBacksteps(source.HostPath.Path, fldPath.Child("hostPath", "path"))...)
+		}
+	}
+	if source.CSI != nil {
+		set++
+		if source.CSI.Driver == "" {
+			errs = append(errs, field.Required(fldPath.Child("csi", "driver"), "driver is required"))
+		}
+	}
+
+	switch set {
+	case 0:
+		errs = append(errs, field.Invalid(fldPath, source,
+			"must specify exactly one of emptyDir, configMap, secret, hostPath or csi"))
+	case 1:
+		// exactly one source set, nothing further to check here
+	default:
+		errs = append(errs, field.Invalid(fldPath, source,
+			"must specify exactly one of emptyDir, configMap, secret, hostPath or csi"))
+	}
+
+	return errs
+}
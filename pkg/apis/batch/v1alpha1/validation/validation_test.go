@@ -0,0 +1,221 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"sort"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	batchv1alpha1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+func int32Ptr(i int32) *int32 { return &i }
+
+func codesInRange(low, high int) []int32 {
+	var codes []int32
+	for i := low; i <= high; i++ {
+		codes = append(codes, int32(i))
+	}
+	return codes
+}
+
+func setToSortedSlice(set exitCodeSet) []int32 {
+	var codes []int32
+	for code := range set {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
+
+func equalInt32Slices(a, b []int32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestParseExitCodeEntry(t *testing.T) {
+	cases := []struct {
+		name    string
+		entry   string
+		want    []int32
+		wantErr bool
+	}{
+		{name: "single code", entry: "137", want: []int32{137}},
+		{name: "inclusive range", entry: "1-5", want: codesInRange(1, 5)},
+		{name: "comma list", entry: "137,139,143", want: []int32{137, 139, 143}},
+		{name: "bare negation matches every code but the excluded one", entry: "!0", want: codesInRange(1, 255)},
+		{name: "range with an excluded code", entry: "1-5,!3", want: []int32{1, 2, 4, 5}},
+		{name: "low greater than high is invalid", entry: "5-1", wantErr: true},
+		{name: "out of bounds code is invalid", entry: "300", wantErr: true},
+		{name: "non-numeric term is invalid", entry: "abc", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseExitCodeEntry(tc.entry)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("parseExitCodeEntry(%q) = %v, want error", tc.entry, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseExitCodeEntry(%q) returned unexpected error: %v", tc.entry, err)
+			}
+			if !equalInt32Slices(setToSortedSlice(got), tc.want) {
+				t.Errorf("parseExitCodeEntry(%q) = %v, want %v", tc.entry, setToSortedSlice(got), tc.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalizeExitCodeRanges(t *testing.T) {
+	cases := []struct {
+		name    string
+		policy  batchv1alpha1.LifecyclePolicy
+		want    []int32
+		wantErr bool
+	}{
+		{
+			name:   "bare exit code",
+			policy: batchv1alpha1.LifecyclePolicy{ExitCode: int32Ptr(137)},
+			want:   []int32{137},
+		},
+		{
+			name:   "exit code ranges entry",
+			policy: batchv1alpha1.LifecyclePolicy{ExitCodeRanges: []string{"137,139,143"}},
+			want:   []int32{137, 139, 143},
+		},
+		{
+			name:   "NonZero group expands to !0",
+			policy: batchv1alpha1.LifecyclePolicy{ExitCodeGroup: batchv1alpha1.NonZeroExitCodeGroup},
+			want:   codesInRange(1, 255),
+		},
+		{
+			name:   "OOMKilled group",
+			policy: batchv1alpha1.LifecyclePolicy{ExitCodeGroup: batchv1alpha1.OOMKilledExitCodeGroup},
+			want:   []int32{137},
+		},
+		{
+			name:    "unknown group is an error",
+			policy:  batchv1alpha1.LifecyclePolicy{ExitCodeGroup: "Bogus"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := canonicalizeExitCodeRanges(tc.policy)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("canonicalizeExitCodeRanges(%+v) = %v, want error", tc.policy, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("canonicalizeExitCodeRanges(%+v) returned unexpected error: %v", tc.policy, err)
+			}
+			if !equalInt32Slices(setToSortedSlice(got), tc.want) {
+				t.Errorf("canonicalizeExitCodeRanges(%+v) = %v, want %v", tc.policy, setToSortedSlice(got), tc.want)
+			}
+		})
+	}
+}
+
+func TestValidatePoliciesExitCodeRanges(t *testing.T) {
+	cases := []struct {
+		name     string
+		policies []batchv1alpha1.LifecyclePolicy
+		wantErrs int
+	}{
+		{
+			name: "range covering 0 is rejected",
+			policies: []batchv1alpha1.LifecyclePolicy{
+				{Action: batchv1alpha1.RestartJobAction, ExitCodeRanges: []string{"0-5"}},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "range covering 0 is accepted once excluded",
+			policies: []batchv1alpha1.LifecyclePolicy{
+				{Action: batchv1alpha1.RestartJobAction, ExitCodeRanges: []string{"0-5,!0"}},
+			},
+			wantErrs: 0,
+		},
+		{
+			name: "two overlapping ranges are rejected",
+			policies: []batchv1alpha1.LifecyclePolicy{
+				{Action: batchv1alpha1.RestartJobAction, ExitCodeRanges: []string{"1-10"}},
+				{Action: batchv1alpha1.AbortJobAction, ExitCodeRanges: []string{"5-15"}},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "a bare ExitCode may fall inside another policy's range",
+			policies: []batchv1alpha1.LifecyclePolicy{
+				{Action: batchv1alpha1.RestartJobAction, ExitCode: int32Ptr(137)},
+				{Action: batchv1alpha1.AbortJobAction, ExitCodeGroup: batchv1alpha1.NonZeroExitCodeGroup},
+			},
+			wantErrs: 0,
+		},
+		{
+			name: "two policies with the same bare ExitCode are rejected",
+			policies: []batchv1alpha1.LifecyclePolicy{
+				{Action: batchv1alpha1.RestartJobAction, ExitCode: int32Ptr(137)},
+				{Action: batchv1alpha1.AbortJobAction, ExitCode: int32Ptr(137)},
+			},
+			wantErrs: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidatePolicies(tc.policies, field.NewPath("spec", "policies"), nil)
+			if len(errs) != tc.wantErrs {
+				t.Errorf("ValidatePolicies(%+v) = %v errors, want %d: %v", tc.policies, len(errs), tc.wantErrs, errs)
+			}
+		})
+	}
+}
+
+func TestMatchExitCodePolicy(t *testing.T) {
+	policies := []batchv1alpha1.LifecyclePolicy{
+		{Action: batchv1alpha1.AbortJobAction, ExitCodeGroup: batchv1alpha1.NonZeroExitCodeGroup},
+		{Action: batchv1alpha1.RestartJobAction, ExitCode: int32Ptr(137)},
+	}
+
+	// A bare ExitCode takes precedence over a matching range, regardless of
+	// which appears first in the list.
+	match := MatchExitCodePolicy(policies, 137)
+	if match == nil || match.Action != batchv1alpha1.RestartJobAction {
+		t.Fatalf("MatchExitCodePolicy(policies, 137) = %v, want the RestartJob policy", match)
+	}
+
+	match = MatchExitCodePolicy(policies, 1)
+	if match == nil || match.Action != batchv1alpha1.AbortJobAction {
+		t.Fatalf("MatchExitCodePolicy(policies, 1) = %v, want the AbortJob policy", match)
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	batchv1alpha1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+// findPolicyStatus returns the LifecyclePolicyStatus tracking trigger within
+// statuses, and its index, or -1 if the chain hasn't started yet.
+func findPolicyStatus(statuses []batchv1alpha1.LifecyclePolicyStatus, trigger string) (batchv1alpha1.LifecyclePolicyStatus, int) {
+	for i, status := range statuses {
+		if status.Event == trigger {
+			return status, i
+		}
+	}
+	return batchv1alpha1.LifecyclePolicyStatus{Event: trigger}, -1
+}
+
+// advanceActionChain walks policy's Actions chain for the given trigger
+// (an Event or a decimal ExitCode), resuming from the step persisted in
+// statuses. It returns the ActionSpec to execute now and the updated
+// LifecyclePolicyStatus to persist back to JobStatus so that, if the
+// controller restarts mid-chain, it resumes instead of starting the chain
+// over from the first step.
+func advanceActionChain(policy batchv1alpha1.LifecyclePolicy, statuses []batchv1alpha1.LifecyclePolicyStatus, trigger string, stepFailed bool) (batchv1alpha1.ActionSpec, batchv1alpha1.LifecyclePolicyStatus, bool) {
+	if len(policy.Actions) == 0 {
+		return batchv1alpha1.ActionSpec{}, batchv1alpha1.LifecyclePolicyStatus{}, false
+	}
+
+	status, _ := findPolicyStatus(statuses, trigger)
+	if status.CurrentStep >= int32(len(policy.Actions)) {
+		status.CurrentStep = int32(len(policy.Actions)) - 1
+	}
+
+	step := policy.Actions[status.CurrentStep]
+	if !stepFailed {
+		return step, status, true
+	}
+
+	if step.MaxRetries > 0 && status.Retries < step.MaxRetries {
+		status.Retries++
+		return step, status, true
+	}
+
+	if status.CurrentStep+1 < int32(len(policy.Actions)) {
+		status.CurrentStep++
+		status.Retries = 0
+		step = policy.Actions[status.CurrentStep]
+	}
+
+	return step, status, true
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"testing"
+
+	batchv1alpha1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+func int32Ptr(i int32) *int32 { return &i }
+
+func twoStepRestartChain() batchv1alpha1.LifecyclePolicy {
+	return batchv1alpha1.LifecyclePolicy{
+		Actions: []batchv1alpha1.ActionSpec{
+			{Action: batchv1alpha1.RestartTaskAction, MaxRetries: 2},
+			{Action: batchv1alpha1.AbortJobAction},
+		},
+	}
+}
+
+func TestAdvanceActionChain(t *testing.T) {
+	policy := twoStepRestartChain()
+
+	// First failure: no status yet, starts at step 0 without consuming a retry.
+	step, status, ok := advanceActionChain(policy, nil, "PodFailed", false)
+	if !ok || step.Action != batchv1alpha1.RestartTaskAction || status.CurrentStep != 0 || status.Retries != 0 {
+		t.Fatalf("first call = (%+v, %+v, %v), want step 0, 0 retries", step, status, ok)
+	}
+
+	// A genuinely new failure of the same trigger retries step 0 until MaxRetries is hit.
+	statuses := []batchv1alpha1.LifecyclePolicyStatus{status}
+	step, status, ok = advanceActionChain(policy, statuses, "PodFailed", true)
+	if !ok || step.Action != batchv1alpha1.RestartTaskAction || status.CurrentStep != 0 || status.Retries != 1 {
+		t.Fatalf("second call = (%+v, %+v, %v), want step 0, 1 retry", step, status, ok)
+	}
+
+	statuses = []batchv1alpha1.LifecyclePolicyStatus{status}
+	step, status, ok = advanceActionChain(policy, statuses, "PodFailed", true)
+	if !ok || step.Action != batchv1alpha1.RestartTaskAction || status.CurrentStep != 0 || status.Retries != 2 {
+		t.Fatalf("third call = (%+v, %+v, %v), want step 0, 2 retries", step, status, ok)
+	}
+
+	// MaxRetries (2) is exhausted: the next failure advances to step 1 and resets Retries.
+	statuses = []batchv1alpha1.LifecyclePolicyStatus{status}
+	step, status, ok = advanceActionChain(policy, statuses, "PodFailed", true)
+	if !ok || step.Action != batchv1alpha1.AbortJobAction || status.CurrentStep != 1 || status.Retries != 0 {
+		t.Fatalf("fourth call = (%+v, %+v, %v), want step 1, 0 retries", step, status, ok)
+	}
+
+	// The chain has no further step past the last one; it stays there.
+	statuses = []batchv1alpha1.LifecyclePolicyStatus{status}
+	step, status, ok = advanceActionChain(policy, statuses, "PodFailed", true)
+	if !ok || step.Action != batchv1alpha1.AbortJobAction || status.CurrentStep != 1 {
+		t.Fatalf("fifth call = (%+v, %+v, %v), want it to remain on the terminal step 1", step, status, ok)
+	}
+}
+
+func TestAdvanceActionChainResumesFromPersistedStep(t *testing.T) {
+	policy := twoStepRestartChain()
+	statuses := []batchv1alpha1.LifecyclePolicyStatus{
+		{Event: "PodFailed", CurrentStep: 1, Retries: 0},
+	}
+
+	// A controller restart must resume from the persisted step, not step 0.
+	step, status, ok := advanceActionChain(policy, statuses, "PodFailed", false)
+	if !ok || step.Action != batchv1alpha1.AbortJobAction || status.CurrentStep != 1 {
+		t.Fatalf("advanceActionChain resumed at %+v, want it to resume at step 1", status)
+	}
+}
+
+func TestAdvanceActionChainNoActions(t *testing.T) {
+	_, _, ok := advanceActionChain(batchv1alpha1.LifecyclePolicy{}, nil, "PodFailed", false)
+	if ok {
+		t.Fatalf("advanceActionChain on a policy with no Actions should report ok=false")
+	}
+}
+
+func TestTakeExitCodeActionIgnoresRestartReobservationOfSamePod(t *testing.T) {
+	cc := &Controller{}
+	policies := []batchv1alpha1.LifecyclePolicy{
+		{ExitCode: int32Ptr(1), Actions: []batchv1alpha1.ActionSpec{
+			{Action: batchv1alpha1.RestartTaskAction, MaxRetries: 1},
+			{Action: batchv1alpha1.AbortJobAction},
+		}},
+	}
+	job := &batchv1alpha1.Job{}
+
+	// First failure of pod "pod-a" starts the chain at step 0.
+	step, err := cc.takeExitCodeAction(job, policies, 1, "pod-a")
+	if err != nil || step == nil || step.Action != batchv1alpha1.RestartTaskAction {
+		t.Fatalf("takeExitCodeAction(pod-a) = (%+v, %v), want step RestartTask", step, err)
+	}
+	if n := len(job.Status.LifecyclePolicyStatuses); n != 1 || job.Status.LifecyclePolicyStatuses[0].Retries != 0 {
+		t.Fatalf("unexpected status after first failure: %+v", job.Status.LifecyclePolicyStatuses)
+	}
+
+	// The controller restarts and re-observes the very same Pod's exit code:
+	// this must not consume a retry or advance the chain.
+	step, err = cc.takeExitCodeAction(job, policies, 1, "pod-a")
+	if err != nil || step == nil || step.Action != batchv1alpha1.RestartTaskAction {
+		t.Fatalf("takeExitCodeAction(pod-a again) = (%+v, %v), want step RestartTask", step, err)
+	}
+	if got := job.Status.LifecyclePolicyStatuses[0].Retries; got != 0 {
+		t.Fatalf("re-observing the same Pod consumed a retry: Retries = %d, want 0", got)
+	}
+
+	// A genuinely new Pod failing with the same exit code is a real retry.
+	step, err = cc.takeExitCodeAction(job, policies, 1, "pod-b")
+	if err != nil || step == nil || step.Action != batchv1alpha1.RestartTaskAction {
+		t.Fatalf("takeExitCodeAction(pod-b) = (%+v, %v), want step RestartTask", step, err)
+	}
+	if got := job.Status.LifecyclePolicyStatuses[0].Retries; got != 1 {
+		t.Fatalf("a new Pod's failure didn't consume a retry: Retries = %d, want 1", got)
+	}
+
+	// MaxRetries (1) is now exhausted: the next new Pod's failure advances the chain.
+	step, err = cc.takeExitCodeAction(job, policies, 1, "pod-c")
+	if err != nil || step == nil || step.Action != batchv1alpha1.AbortJobAction {
+		t.Fatalf("takeExitCodeAction(pod-c) = (%+v, %v), want step AbortJob", step, err)
+	}
+}
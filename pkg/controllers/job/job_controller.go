@@ -0,0 +1,40 @@
+/*
+Copyright 2018 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	vcclientset "volcano.sh/volcano/pkg/client/clientset/versioned"
+)
+
+// Controller reconciles Job objects, creating and managing the PodGroup and
+// Pods that back them.
+type Controller struct {
+	kubeClient kubernetes.Interface
+	vcClient   vcclientset.Interface
+
+	jobInformerSynced cache.InformerSynced
+	podInformerSynced cache.InformerSynced
+	pgInformerSynced  cache.InformerSynced
+
+	queue    workqueue.RateLimitingInterface
+	recorder record.EventRecorder
+}
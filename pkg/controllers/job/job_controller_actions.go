@@ -0,0 +1,39 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	batchv1alpha1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+// createJob is the entry point of the reconcile path for a newly observed or
+// updated Job: it is invoked before any PodGroup/Pod is created for job.
+// validateJobSpec runs first and unconditionally, so a Job that reached the
+// API server without going through the admission webhook (failurePolicy:
+// Ignore, `kubectl --validate=false`, a GitOps sync) is still refused here
+// instead of being reconciled into cryptic Pod/Task failures.
+func (cc *Controller) createJob(job *batchv1alpha1.Job) error {
+	if err := validateJobSpec(job, cc.recorder); err != nil {
+		return err
+	}
+
+	if err := cc.createPodGroupIfNotExist(job); err != nil {
+		return err
+	}
+
+	return cc.createPods(job)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	batchv1alpha1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/apis/batch/v1alpha1/validation"
+)
+
+// policyForExitCode returns the policy, if any, that governs a container
+// observed to have exited with exitCode. It defers to
+// validation.MatchExitCodePolicy so the precedence rules enforced at
+// admission/reconcile time (a bare ExitCode wins over a matching
+// ExitCodeRanges/ExitCodeGroup) are also what the controller acts on.
+func policyForExitCode(policies []batchv1alpha1.LifecyclePolicy, exitCode int32) *batchv1alpha1.LifecyclePolicy {
+	return validation.MatchExitCodePolicy(policies, exitCode)
+}
+
+// takeExitCodeAction is called from the reconcile path when podUID is
+// observed to have exited with exitCode. It resolves the governing policy,
+// walks (or resumes) its Actions chain via advanceActionChain, persists the
+// chain's current step into job.Status.LifecyclePolicyStatuses, and returns
+// the ActionSpec the caller should execute now. A nil result means no
+// policy's Actions chain applies to this exit code.
+//
+// podUID identifies the specific Pod whose container produced exitCode.
+// Reconciling the very same Pod's terminal state again - most commonly
+// because the controller restarted and re-lists it - must not consume
+// another retry or advance the chain a second time, so advanceActionChain is
+// only told the step failed when podUID differs from the one already
+// recorded for this trigger.
+func (cc *Controller) takeExitCodeAction(job *batchv1alpha1.Job, policies []batchv1alpha1.LifecyclePolicy, exitCode int32, podUID types.UID) (*batchv1alpha1.ActionSpec, error) {
+	policy := policyForExitCode(policies, exitCode)
+	if policy == nil || len(policy.Actions) == 0 {
+		return nil, nil
+	}
+
+	trigger := strconv.Itoa(int(exitCode))
+	existing, found := findPolicyStatus(job.Status.LifecyclePolicyStatuses, trigger)
+	stepFailed := found >= 0 && existing.LastPodUID != string(podUID)
+
+	step, status, ok := advanceActionChain(*policy, job.Status.LifecyclePolicyStatuses, trigger, stepFailed)
+	if !ok {
+		return nil, nil
+	}
+	status.LastPodUID = string(podUID)
+
+	job.Status.LifecyclePolicyStatuses = upsertPolicyStatus(job.Status.LifecyclePolicyStatuses, status)
+	return &step, nil
+}
+
+// upsertPolicyStatus replaces the LifecyclePolicyStatus tracking status's
+// trigger, or appends it if the chain hasn't been recorded yet.
+func upsertPolicyStatus(statuses []batchv1alpha1.LifecyclePolicyStatus, status batchv1alpha1.LifecyclePolicyStatus) []batchv1alpha1.LifecyclePolicyStatus {
+	for i := range statuses {
+		if statuses[i].Event == status.Event {
+			statuses[i] = status
+			return statuses
+		}
+	}
+	return append(statuses, status)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
+
+	batchv1alpha1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/apis/batch/v1alpha1/validation"
+)
+
+// validateJobSpec re-runs the same checks the admission webhook performs
+// against job's lifecycle policies and volume specs. It is called from the
+// reconcile path before any Pod/PodGroup is created, so a Job that reached
+// the API server without going through the webhook (failurePolicy: Ignore,
+// `kubectl --validate=false`, a GitOps sync) is still caught before it does
+// any damage instead of surfacing as a cryptic Pod/Task failure later on.
+func validateJobSpec(job *batchv1alpha1.Job, recorder record.EventRecorder) error {
+	var errs field.ErrorList
+
+	specPath := field.NewPath("spec")
+	errs = append(errs, validation.ValidatePolicies(job.Spec.Policies, specPath.Child("policies"), job.Spec.ActiveDeadlineSeconds)...)
+	errs = append(errs, validation.ValidateIO(job.Spec.Volumes, specPath.Child("volumes"))...)
+
+	tasksPath := specPath.Child("tasks")
+	for index, task := range job.Spec.Tasks {
+		errs = append(errs, validation.ValidatePolicies(task.Policies, tasksPath.Index(index).Child("policies"), job.Spec.ActiveDeadlineSeconds)...)
+	}
+
+	if err := errs.ToAggregate(); err != nil {
+		recorder.Eventf(job, v1.EventTypeWarning, "ValidationFailed", "%s", err.Error())
+		return err
+	}
+
+	return nil
+}
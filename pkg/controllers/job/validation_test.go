@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"testing"
+
+	"k8s.io/client-go/tools/record"
+
+	batchv1alpha1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+func TestValidateJobSpec(t *testing.T) {
+	cases := []struct {
+		name    string
+		job     *batchv1alpha1.Job
+		wantErr bool
+	}{
+		{
+			name: "valid job-level and task-level policies",
+			job: &batchv1alpha1.Job{
+				Spec: batchv1alpha1.JobSpec{
+					Policies: []batchv1alpha1.LifecyclePolicy{
+						{Action: batchv1alpha1.RestartJobAction, Event: batchv1alpha1.PodFailedEvent},
+					},
+					Tasks: []batchv1alpha1.TaskSpec{
+						{Name: "worker", Policies: []batchv1alpha1.LifecyclePolicy{
+							{Action: batchv1alpha1.RestartTaskAction, Event: batchv1alpha1.TaskCompletedEvent},
+						}},
+					},
+				},
+			},
+		},
+		{
+			name: "job-level policy with neither an event nor an exit-code matcher",
+			job: &batchv1alpha1.Job{
+				Spec: batchv1alpha1.JobSpec{
+					Policies: []batchv1alpha1.LifecyclePolicy{
+						{Action: batchv1alpha1.RestartJobAction},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "task-level policy using an event that isn't externally allowed",
+			job: &batchv1alpha1.Job{
+				Spec: batchv1alpha1.JobSpec{
+					Tasks: []batchv1alpha1.TaskSpec{
+						{Name: "worker", Policies: []batchv1alpha1.LifecyclePolicy{
+							{Action: batchv1alpha1.RestartTaskAction, Event: batchv1alpha1.OutOfSyncEvent},
+						}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "volume spec missing a mountPath",
+			job: &batchv1alpha1.Job{
+				Spec: batchv1alpha1.JobSpec{
+					Volumes: []batchv1alpha1.VolumeSpec{{VolumeClaimName: "my-pvc"}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(1)
+			err := validateJobSpec(tc.job, recorder)
+
+			if tc.wantErr && err == nil {
+				t.Fatalf("validateJobSpec(%+v) = nil, want error", tc.job)
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("validateJobSpec(%+v) returned unexpected error: %v", tc.job, err)
+			}
+
+			select {
+			case event := <-recorder.Events:
+				if !tc.wantErr {
+					t.Fatalf("validateJobSpec recorded an event for a valid Job: %s", event)
+				}
+			default:
+				if tc.wantErr {
+					t.Fatalf("validateJobSpec returned an error but recorded no ValidationFailed event")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2018 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	batchv1alpha1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+// ValidateJob validates a Job's spec, including its own and its tasks'
+// lifecycle policies and its volume specs, and is the webhook's entry point
+// for ValidatingAdmissionWebhook requests against batch/v1alpha1 Jobs.
+func ValidateJob(job *batchv1alpha1.Job) error {
+	specPath := field.NewPath("spec")
+
+	if err := validatePolicies(job.Spec.Policies, specPath.Child("policies"), job.Spec.ActiveDeadlineSeconds); err != nil {
+		return err
+	}
+	if err := validateIO(job.Spec.Volumes); err != nil {
+		return err
+	}
+
+	tasksPath := specPath.Child("tasks")
+	for index, task := range job.Spec.Tasks {
+		if err := validatePolicies(task.Policies, tasksPath.Index(index).Child("policies"), job.Spec.ActiveDeadlineSeconds); err != nil {
+			return fmt.Errorf("task[%d](%s): %v", index, task.Name, err)
+		}
+	}
+
+	return nil
+}